@@ -0,0 +1,215 @@
+package statsmgr
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DiskUsageBackend reports the bytes and inodes used by a container's
+// writable layer. Each storage driver locates and measures that layer
+// differently, so the server selects one implementation once, on first use,
+// based on the configured storage driver, and buildContainerStats calls it
+// unconditionally, rather than special-casing overlay.
+type DiskUsageBackend interface {
+	// DiskUsage returns the bytes and inodes used by the writable layer of
+	// the container mounted at mountPoint (as returned by
+	// oci.Container.MountPoint()).
+	DiskUsage(mountPoint string) (bytesUsed, inodesUsed uint64, err error)
+}
+
+// NewDiskUsageBackend selects the DiskUsageBackend matching the configured
+// storage driver. driverOptions are the raw `storage_option` strings from
+// storage.conf, used to detect project-quota-enabled overlay and to locate
+// the devmapper thin pool.
+func NewDiskUsageBackend(driver string, driverOptions []string) (DiskUsageBackend, error) {
+	switch driver {
+	case "overlay", "overlay2":
+		if hasOverlayQuota(driverOptions) {
+			return &quotaDiskUsageBackend{}, nil
+		}
+		return &overlayDiskUsageBackend{}, nil
+	case "btrfs":
+		return &btrfsDiskUsageBackend{}, nil
+	case "zfs":
+		return &zfsDiskUsageBackend{}, nil
+	case "devicemapper":
+		return &devmapperDiskUsageBackend{thinPoolDevice: driverOption(driverOptions, "dm.thinpooldev")}, nil
+	case "vfs":
+		return &vfsDiskUsageBackend{}, nil
+	default:
+		return nil, errors.Errorf("no disk usage backend for storage driver %q", driver)
+	}
+}
+
+// NewNoopDiskUsageBackend returns a backend that always reports zero usage.
+// It is used as a safe fallback when the configured storage driver has no
+// matching DiskUsageBackend, so a misconfiguration degrades WritableLayer
+// reporting instead of breaking ContainerStats entirely.
+func NewNoopDiskUsageBackend() DiskUsageBackend {
+	return &noopDiskUsageBackend{}
+}
+
+type noopDiskUsageBackend struct{}
+
+func (b *noopDiskUsageBackend) DiskUsage(string) (uint64, uint64, error) {
+	return 0, 0, nil
+}
+
+func hasOverlayQuota(driverOptions []string) bool {
+	for _, opt := range driverOptions {
+		if strings.Contains(opt, "overlay.mountopt") && strings.Contains(opt, "pquota") {
+			return true
+		}
+		if strings.Contains(opt, "overlay2.mountopt") && strings.Contains(opt, "pquota") {
+			return true
+		}
+	}
+	return false
+}
+
+func driverOption(driverOptions []string, key string) string {
+	prefix := key + "="
+	for _, opt := range driverOptions {
+		if strings.HasPrefix(opt, prefix) {
+			return strings.TrimPrefix(opt, prefix)
+		}
+	}
+	return ""
+}
+
+// overlayDiskUsageBackend walks the container's overlay diff directory,
+// which is the pre-quota behavior buildContainerStats always used to have.
+type overlayDiskUsageBackend struct{}
+
+func (b *overlayDiskUsageBackend) DiskUsage(mountPoint string) (uint64, uint64, error) {
+	diffDir := filepath.Join(filepath.Dir(mountPoint), "diff")
+	return GetDiskUsageStats(diffDir)
+}
+
+// vfsDiskUsageBackend walks the container's mount point directly. Unlike
+// overlay, vfs has no separate upper/diff layer: mountPoint is a full copy
+// of the rootfs, so that is what must be measured.
+type vfsDiskUsageBackend struct{}
+
+func (b *vfsDiskUsageBackend) DiskUsage(mountPoint string) (uint64, uint64, error) {
+	return GetDiskUsageStats(mountPoint)
+}
+
+// quotaDiskUsageBackend reads the project quota CRI-O's storage driver
+// already assigns to each overlay diff dir via a Project ID xattr, instead
+// of walking the directory tree.
+type quotaDiskUsageBackend struct{}
+
+func (b *quotaDiskUsageBackend) DiskUsage(mountPoint string) (uint64, uint64, error) {
+	diffDir := filepath.Join(filepath.Dir(mountPoint), "diff")
+	return getProjectQuotaUsage(diffDir)
+}
+
+// btrfsDiskUsageBackend shells out to `btrfs qgroup show`, which is how
+// containers/storage itself tracks per-subvolume usage on btrfs.
+type btrfsDiskUsageBackend struct{}
+
+func (b *btrfsDiskUsageBackend) DiskUsage(mountPoint string) (uint64, uint64, error) {
+	out, err := exec.Command("btrfs", "qgroup", "show", "-r", "--raw", mountPoint).Output()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "run btrfs qgroup show")
+	}
+	bytesUsed, err := parseBtrfsQgroupShow(string(out))
+	if err != nil {
+		return 0, 0, err
+	}
+	// btrfs does not expose a separate inode count per subvolume.
+	return bytesUsed, 0, nil
+}
+
+func parseBtrfsQgroupShow(output string) (uint64, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) < 2 {
+		return 0, errors.New("unexpected btrfs qgroup show output")
+	}
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(fields) < 3 {
+		return 0, errors.New("unexpected btrfs qgroup show output")
+	}
+	return strconv.ParseUint(fields[2], 10, 64)
+}
+
+// zfsDiskUsageBackend shells out to `zfs get`, reading the dataset name from
+// the container's mount point the same way containers/storage derives it.
+type zfsDiskUsageBackend struct{}
+
+func (b *zfsDiskUsageBackend) DiskUsage(mountPoint string) (uint64, uint64, error) {
+	dataset := zfsDatasetForMountPoint(mountPoint)
+	out, err := exec.Command("zfs", "get", "-Hp", "-o", "value", "used,usedbyrefreservation", dataset).Output()
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "run zfs get on %s", dataset)
+	}
+	var total uint64
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		v, err := strconv.ParseUint(strings.TrimSpace(line), 10, 64)
+		if err != nil {
+			continue
+		}
+		total += v
+	}
+	return total, 0, nil
+}
+
+func zfsDatasetForMountPoint(mountPoint string) string {
+	// containers/storage names each layer's dataset after the layer ID,
+	// which is the final path component of its mount point.
+	return filepath.Base(mountPoint)
+}
+
+// devmapperDiskUsageBackend reads each container's own thin device usage via
+// dmsetup, since devicemapper containers have no directory to walk at all.
+type devmapperDiskUsageBackend struct {
+	thinPoolDevice string
+}
+
+func (b *devmapperDiskUsageBackend) DiskUsage(mountPoint string) (uint64, uint64, error) {
+	if b.thinPoolDevice == "" {
+		return 0, 0, errors.New("devicemapper disk usage backend requires dm.thinpooldev to be configured")
+	}
+
+	// mountPoint looks like <driver-home>/mnt/<layer-id>/rootfs; the
+	// per-container thin device containers/storage sets up is named after
+	// the thin pool plus that layer ID, not the mountpoint's own basename
+	// ("rootfs"), which is not a dm device at all.
+	layerID := filepath.Base(filepath.Dir(mountPoint))
+	deviceName := fmt.Sprintf("%s-%s", filepath.Base(b.thinPoolDevice), layerID)
+
+	out, err := exec.Command("dmsetup", "status", deviceName).Output()
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "run dmsetup status on %s", deviceName)
+	}
+	bytesUsed, err := parseDmsetupStatus(string(out))
+	if err != nil {
+		return 0, 0, err
+	}
+	return bytesUsed, 0, nil
+}
+
+func parseDmsetupStatus(output string) (uint64, error) {
+	// deviceName above names a per-container thin volume, not the thin pool
+	// itself, so its `dmsetup status` line uses the "thin" target type:
+	//   <start> <length> thin <mapped sectors> <highest mapped sector>
+	// (the thin-pool device's own status line is shaped differently, with a
+	// "thin-pool" target and used/total metadata and data block counts, but
+	// that's never what's queried here).
+	fields := strings.Fields(output)
+	if len(fields) < 5 || fields[2] != "thin" {
+		return 0, errors.New("unexpected dmsetup status output for a thin device")
+	}
+	mappedSectors, err := strconv.ParseUint(fields[3], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	const sectorSizeBytes = 512
+	return mappedSectors * sectorSizeBytes, nil
+}
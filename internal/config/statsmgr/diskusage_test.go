@@ -0,0 +1,71 @@
+package statsmgr
+
+import "testing"
+
+func TestParseBtrfsQgroupShow(t *testing.T) {
+	output := "qgroupid         rfer         excl \n" +
+		"--------         ----         ---- \n" +
+		"0/257        16777216     16777216 \n"
+
+	bytesUsed, err := parseBtrfsQgroupShow(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytesUsed != 16777216 {
+		t.Errorf("expected 16777216, got %d", bytesUsed)
+	}
+}
+
+func TestParseBtrfsQgroupShowMalformed(t *testing.T) {
+	if _, err := parseBtrfsQgroupShow("garbage"); err == nil {
+		t.Fatal("expected an error for malformed output, got nil")
+	}
+}
+
+func TestParseDmsetupStatus(t *testing.T) {
+	output := "0 209715200 thin 427786 209715199\n"
+
+	bytesUsed, err := parseDmsetupStatus(output)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	const sectorSizeBytes = 512
+	if want := uint64(427786) * sectorSizeBytes; bytesUsed != want {
+		t.Errorf("expected %d, got %d", want, bytesUsed)
+	}
+}
+
+func TestParseDmsetupStatusMalformed(t *testing.T) {
+	if _, err := parseDmsetupStatus("0 204800 thin"); err == nil {
+		t.Fatal("expected an error for malformed output, got nil")
+	}
+}
+
+func TestParseDmsetupStatusWrongTargetType(t *testing.T) {
+	// The thin-pool device's own status line, not a per-container thin
+	// volume's -- parseDmsetupStatus must reject it rather than silently
+	// misreading a field from the wrong layout.
+	output := "0 204800 thin-pool 1 12345/2048000 678/409600\n"
+	if _, err := parseDmsetupStatus(output); err == nil {
+		t.Fatal("expected an error for a thin-pool status line, got nil")
+	}
+}
+
+func TestHasOverlayQuota(t *testing.T) {
+	if !hasOverlayQuota([]string{"overlay.mountopt=pquota"}) {
+		t.Error("expected pquota mount option to be detected")
+	}
+	if hasOverlayQuota([]string{"overlay.size=10G"}) {
+		t.Error("did not expect non-quota mount option to be detected")
+	}
+}
+
+func TestDriverOption(t *testing.T) {
+	got := driverOption([]string{"dm.thinpooldev=/dev/mapper/thin-pool"}, "dm.thinpooldev")
+	if got != "/dev/mapper/thin-pool" {
+		t.Errorf("expected /dev/mapper/thin-pool, got %q", got)
+	}
+	if driverOption(nil, "dm.thinpooldev") != "" {
+		t.Error("expected empty string for missing option")
+	}
+}
@@ -0,0 +1,168 @@
+package statsmgr
+
+import (
+	"sync"
+	"time"
+)
+
+// Sample is a single point-in-time measurement of a container or sandbox's
+// resource usage. Two consecutive samples are kept per ID so that a rate
+// (e.g. UsageNanoCores) can be derived without CRI-O having to shell into
+// the runtime or stat the filesystem on every kubelet poll.
+type Sample struct {
+	Timestamp       int64
+	CPUNano         uint64
+	WorkingSetBytes uint64
+	DiskUsedBytes   uint64
+	DiskInodesUsed  uint64
+	// ProcessCount is only populated for sandbox samples; containers report
+	// it as 0 since ContainerStats has no equivalent field.
+	ProcessCount uint64
+}
+
+// Rate is the latest sample for an ID, plus the CPU usage rate derived from
+// it and the sample collected before it.
+type Rate struct {
+	Current        *Sample
+	UsageNanoCores uint64
+}
+
+// CollectFunc retrieves a fresh Sample for the given container or sandbox
+// ID. It is supplied by the caller constructing the Manager so that this
+// package stays agnostic of how a particular ID's cgroup or diff dir is
+// located.
+type CollectFunc func(id string) (*Sample, error)
+
+// Manager periodically samples the resource usage of every ID it is told to
+// track via Track, retaining the last two samples for each so Get/List can
+// report a monotonic UsageNanoCores rate. A Manager created with a period of
+// 0 is disabled: Start is a no-op and Get/List always return nil/empty so
+// that callers fall back to their own synchronous collection path.
+type Manager struct {
+	period  time.Duration
+	collect CollectFunc
+
+	mu      sync.Mutex
+	ids     map[string]struct{}
+	samples map[string][2]*Sample // index 0 is the previous sample, 1 the latest
+
+	stop chan struct{}
+}
+
+// NewManager creates a Manager that samples every period using collect.
+func NewManager(period time.Duration, collect CollectFunc) *Manager {
+	return &Manager{
+		period:  period,
+		collect: collect,
+		ids:     make(map[string]struct{}),
+		samples: make(map[string][2]*Sample),
+		stop:    make(chan struct{}),
+	}
+}
+
+// Enabled reports whether background collection is turned on.
+func (m *Manager) Enabled() bool {
+	return m != nil && m.period > 0
+}
+
+// Start begins the background collection loop. It is a no-op if the manager
+// is disabled.
+func (m *Manager) Start() {
+	if !m.Enabled() {
+		return
+	}
+	go m.loop()
+}
+
+// Stop terminates the background collection loop. It is a no-op if the
+// manager is disabled.
+func (m *Manager) Stop() {
+	if !m.Enabled() {
+		return
+	}
+	close(m.stop)
+}
+
+// Track adds id to the set of containers/sandboxes sampled on every tick.
+func (m *Manager) Track(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ids[id] = struct{}{}
+}
+
+func (m *Manager) loop() {
+	ticker := time.NewTicker(m.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.collectAll()
+		}
+	}
+}
+
+func (m *Manager) collectAll() {
+	m.mu.Lock()
+	ids := make([]string, 0, len(m.ids))
+	for id := range m.ids {
+		ids = append(ids, id)
+	}
+	m.mu.Unlock()
+
+	for _, id := range ids {
+		sample, err := m.collect(id)
+		if err != nil {
+			continue
+		}
+		m.mu.Lock()
+		pair := m.samples[id]
+		pair[0], pair[1] = pair[1], sample
+		m.samples[id] = pair
+		m.mu.Unlock()
+	}
+}
+
+// Get returns the cached rate for id, or nil if collection is disabled or id
+// has not been sampled at least twice yet.
+func (m *Manager) Get(id string) *Rate {
+	if !m.Enabled() {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pair, ok := m.samples[id]
+	if !ok || pair[0] == nil || pair[1] == nil {
+		return nil
+	}
+	return rateFromSamples(pair[0], pair[1])
+}
+
+// List returns the cached rate for every tracked ID that has been sampled at
+// least twice. It returns an empty map if collection is disabled.
+func (m *Manager) List() map[string]*Rate {
+	rates := make(map[string]*Rate)
+	if !m.Enabled() {
+		return rates
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, pair := range m.samples {
+		if pair[0] == nil || pair[1] == nil {
+			continue
+		}
+		rates[id] = rateFromSamples(pair[0], pair[1])
+	}
+	return rates
+}
+
+func rateFromSamples(prev, cur *Sample) *Rate {
+	rate := &Rate{Current: cur}
+	dt := cur.Timestamp - prev.Timestamp
+	if dt <= 0 || cur.CPUNano < prev.CPUNano {
+		return rate
+	}
+	rate.UsageNanoCores = (cur.CPUNano - prev.CPUNano) * uint64(time.Second) / uint64(dt)
+	return rate
+}
@@ -0,0 +1,57 @@
+package statsmgr
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateFromSamples(t *testing.T) {
+	prev := &Sample{Timestamp: 0, CPUNano: 1_000_000_000}
+	cur := &Sample{Timestamp: int64(time.Second), CPUNano: 1_500_000_000}
+
+	rate := rateFromSamples(prev, cur)
+	if rate.Current != cur {
+		t.Errorf("expected Current to be cur, got %+v", rate.Current)
+	}
+	if rate.UsageNanoCores != 500_000_000 {
+		t.Errorf("expected 500000000 nanocores, got %d", rate.UsageNanoCores)
+	}
+}
+
+func TestRateFromSamplesNonMonotonic(t *testing.T) {
+	prev := &Sample{Timestamp: int64(time.Second), CPUNano: 2_000_000_000}
+	cur := &Sample{Timestamp: 0, CPUNano: 1_000_000_000}
+
+	rate := rateFromSamples(prev, cur)
+	if rate.UsageNanoCores != 0 {
+		t.Errorf("expected 0 nanocores for a non-monotonic sample pair, got %d", rate.UsageNanoCores)
+	}
+}
+
+func TestManagerGetBeforeSecondSample(t *testing.T) {
+	m := NewManager(time.Second, func(id string) (*Sample, error) {
+		return &Sample{Timestamp: 1, CPUNano: 1}, nil
+	})
+	m.Track("abc")
+	m.collectAll()
+	if got := m.Get("abc"); got != nil {
+		t.Errorf("expected nil after only one sample, got %+v", got)
+	}
+}
+
+func TestManagerDisabled(t *testing.T) {
+	m := NewManager(0, func(id string) (*Sample, error) {
+		return &Sample{Timestamp: 1, CPUNano: 1}, nil
+	})
+	if m.Enabled() {
+		t.Error("expected manager with a 0 period to be disabled")
+	}
+	m.Start()
+	m.Track("abc")
+	if got := m.Get("abc"); got != nil {
+		t.Errorf("expected disabled manager to always return nil, got %+v", got)
+	}
+	if len(m.List()) != 0 {
+		t.Errorf("expected disabled manager to return an empty list")
+	}
+}
@@ -0,0 +1,24 @@
+package statsmgr
+
+import (
+	"github.com/containers/storage/pkg/quota"
+	"github.com/pkg/errors"
+)
+
+// quotaDiskUsageBackend's DiskUsage reads the project quota usage that
+// containers/storage already assigns to each overlay diff dir when pquota is
+// enabled, via the same pkg/quota control containers/storage itself uses to
+// set the quota, rather than walking the directory tree.
+func getProjectQuotaUsage(path string) (bytesUsed, inodesUsed uint64, err error) {
+	ctrl, err := quota.NewControl(path)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "open quota control for %s", path)
+	}
+
+	var q quota.Quota
+	if err := ctrl.GetQuota(path, &q); err != nil {
+		return 0, 0, errors.Wrapf(err, "get quota usage for %s", path)
+	}
+
+	return q.Size, q.Inodes, nil
+}
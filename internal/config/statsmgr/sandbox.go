@@ -0,0 +1,194 @@
+package statsmgr
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/config/node"
+	"github.com/pkg/errors"
+	"github.com/vishvananda/netlink"
+	"github.com/vishvananda/netns"
+)
+
+// SandboxCgroupStats is the CPU and memory usage accounted directly against
+// a sandbox's cgroup, i.e. the sum of every container placed under it.
+type SandboxCgroupStats struct {
+	CPUNano         uint64
+	WorkingSetBytes uint64
+	ProcessCount    uint64
+}
+
+// GetSandboxCgroupStats reads the CPU, memory and task count accounted against
+// cgroupParent, transparently handling both cgroup v1 and cgroup v2
+// hierarchies.
+func GetSandboxCgroupStats(cgroupParent string) (*SandboxCgroupStats, error) {
+	if node.CgroupIsV2() {
+		return sandboxCgroupStatsV2(cgroupParent)
+	}
+	return sandboxCgroupStatsV1(cgroupParent)
+}
+
+func sandboxCgroupStatsV2(cgroupParent string) (*SandboxCgroupStats, error) {
+	root := filepath.Join(cgroupV2MountPoint, cgroupParent)
+
+	cpuNano, err := readCgroupV2CPUUsage(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "read cgroup v2 cpu usage")
+	}
+
+	memory, err := readUint64File(filepath.Join(root, "memory.current"))
+	if err != nil {
+		return nil, errors.Wrap(err, "read cgroup v2 memory usage")
+	}
+
+	procs, err := countCgroupProcesses(root, "cgroup.procs")
+	if err != nil {
+		return nil, errors.Wrap(err, "count cgroup v2 processes")
+	}
+
+	return &SandboxCgroupStats{CPUNano: cpuNano, WorkingSetBytes: memory, ProcessCount: procs}, nil
+}
+
+func sandboxCgroupStatsV1(cgroupParent string) (*SandboxCgroupStats, error) {
+	cpuAcctRoot := filepath.Join(cgroupV1MountPoint, "cpuacct", cgroupParent)
+	memoryRoot := filepath.Join(cgroupV1MountPoint, "memory", cgroupParent)
+
+	cpuNano, err := readUint64File(filepath.Join(cpuAcctRoot, "cpuacct.usage"))
+	if err != nil {
+		return nil, errors.Wrap(err, "read cgroup v1 cpu usage")
+	}
+
+	memory, err := readUint64File(filepath.Join(memoryRoot, "memory.usage_in_bytes"))
+	if err != nil {
+		return nil, errors.Wrap(err, "read cgroup v1 memory usage")
+	}
+
+	procs, err := countCgroupProcesses(memoryRoot, "tasks")
+	if err != nil {
+		return nil, errors.Wrap(err, "count cgroup v1 processes")
+	}
+
+	return &SandboxCgroupStats{CPUNano: cpuNano, WorkingSetBytes: memory, ProcessCount: procs}, nil
+}
+
+func readCgroupV2CPUUsage(root string) (uint64, error) {
+	data, err := os.ReadFile(filepath.Join(root, "cpu.stat"))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == "usage_usec" {
+			usec, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, err
+			}
+			return usec * 1000, nil
+		}
+	}
+	return 0, errors.Errorf("usage_usec not found in %s", root)
+}
+
+func readUint64File(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// countCgroupProcesses counts the tasks listed in the named cgroup
+// membership file (cgroup.procs for v2, tasks for v1) of root and every
+// descendant cgroup, since container cgroups live underneath the sandbox's.
+func countCgroupProcesses(root, fileName string) (uint64, error) {
+	var count uint64
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || info.Name() != fileName {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line != "" {
+				count++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// InterfaceStats is the set of counters tracked for a single network
+// interface inside a sandbox's network namespace.
+type InterfaceStats struct {
+	Name     string
+	RxBytes  uint64
+	RxErrors uint64
+	TxBytes  uint64
+	TxErrors uint64
+}
+
+// GetSandboxNetworkStats resolves interface counters from inside the network
+// namespace at netNsPath. The first non-loopback interface is treated as the
+// default, matching how the kubelet summary API reports pod network stats.
+func GetSandboxNetworkStats(netNsPath string) (defaultIface *InterfaceStats, all []*InterfaceStats, err error) {
+	if netNsPath == "" {
+		return nil, nil, nil
+	}
+
+	ns, err := netns.GetFromPath(netNsPath)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "get netns from %s", netNsPath)
+	}
+	defer ns.Close()
+
+	handle, err := netlink.NewHandleAt(ns)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "open netlink handle for %s", netNsPath)
+	}
+	defer handle.Delete()
+
+	links, err := handle.LinkList()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "list links")
+	}
+
+	for _, link := range links {
+		attrs := link.Attrs()
+		if attrs.Name == "lo" {
+			continue
+		}
+		stats := attrs.Statistics
+		if stats == nil {
+			continue
+		}
+		iface := &InterfaceStats{
+			Name:     attrs.Name,
+			RxBytes:  stats.RxBytes,
+			RxErrors: stats.RxErrors,
+			TxBytes:  stats.TxBytes,
+			TxErrors: stats.TxErrors,
+		}
+		all = append(all, iface)
+		if defaultIface == nil {
+			defaultIface = iface
+		}
+	}
+
+	return defaultIface, all, nil
+}
+
+const (
+	cgroupV1MountPoint = "/sys/fs/cgroup"
+	cgroupV2MountPoint = "/sys/fs/cgroup"
+)
@@ -0,0 +1,35 @@
+package oci
+
+import (
+	"sync"
+
+	"github.com/cri-o/cri-o/server/cri/types"
+)
+
+// appliedLinuxResources caches the LinuxContainerResources most recently
+// applied to each container by UpdateContainerResources, keyed by container
+// ID. It is tracked here rather than as a field on Container because
+// Container's definition lives outside this change.
+var (
+	appliedLinuxResourcesMu sync.Mutex
+	appliedLinuxResources   = map[string]*types.LinuxContainerResources{}
+)
+
+// SetLinuxResources records the LinuxContainerResources that were
+// successfully applied to the container by the most recent
+// UpdateContainerResources call, so that ContainerStatus can report back
+// what is actually in force rather than what was originally requested at
+// create time.
+func (c *Container) SetLinuxResources(r *types.LinuxContainerResources) {
+	appliedLinuxResourcesMu.Lock()
+	defer appliedLinuxResourcesMu.Unlock()
+	appliedLinuxResources[c.ID()] = r
+}
+
+// LinuxResources returns the LinuxContainerResources currently applied to
+// the container, or nil if it has never been live-updated.
+func (c *Container) LinuxResources() *types.LinuxContainerResources {
+	appliedLinuxResourcesMu.Lock()
+	defer appliedLinuxResourcesMu.Unlock()
+	return appliedLinuxResources[c.ID()]
+}
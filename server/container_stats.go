@@ -2,7 +2,6 @@ package server
 
 import (
 	"context"
-	"path/filepath"
 
 	"github.com/cri-o/cri-o/internal/config/statsmgr"
 	"github.com/cri-o/cri-o/internal/log"
@@ -33,7 +32,15 @@ func (s *Server) ContainerStats(ctx context.Context, req *types.ContainerStatsRe
 func (s *Server) CRIStatsForContainers(ctx context.Context, containers ...*oci.Container) ([]*types.ContainerStats, []error) {
 	stats := make([]*types.ContainerStats, 0)
 	errs := make([]error, 0)
+	mgr := s.StatsManager()
 	for _, c := range containers {
+		mgr.Track(c.ID())
+
+		if rate := mgr.Get(c.ID()); rate != nil {
+			stats = append(stats, s.buildContainerStatsFromSample(c, rate))
+			continue
+		}
+
 		sb := s.GetSandbox(c.Sandbox())
 		if sb == nil {
 			errs = append(errs, errors.Errorf("unable to get stats for container %s: sandbox %s not found", c.ID(), c.Sandbox()))
@@ -51,24 +58,52 @@ func (s *Server) CRIStatsForContainers(ctx context.Context, containers ...*oci.C
 	return stats, errs
 }
 
+// buildContainerStatsFromSample builds ContainerStats from the statsmgr
+// background collector's cached sample, reporting UsageNanoCores as the rate
+// derived between the two most recent samples instead of leaving it unset.
+func (s *Server) buildContainerStatsFromSample(container *oci.Container, rate *statsmgr.Rate) *types.ContainerStats {
+	sample := rate.Current
+	return &types.ContainerStats{
+		Attributes: &types.ContainerAttributes{
+			ID: container.ID(),
+			Metadata: &types.ContainerMetadata{
+				Name:    container.Metadata().Name,
+				Attempt: container.Metadata().Attempt,
+			},
+			Labels:      container.Labels(),
+			Annotations: container.Annotations(),
+		},
+		CPU: &types.CPUUsage{
+			Timestamp:            sample.Timestamp,
+			UsageCoreNanoSeconds: &types.UInt64Value{Value: sample.CPUNano},
+			UsageNanoCores:       &types.UInt64Value{Value: rate.UsageNanoCores},
+		},
+		Memory: &types.MemoryUsage{
+			Timestamp:       sample.Timestamp,
+			WorkingSetBytes: &types.UInt64Value{Value: sample.WorkingSetBytes},
+		},
+		WritableLayer: &types.FilesystemUsage{
+			Timestamp:  sample.Timestamp,
+			FsID:       &types.FilesystemIdentifier{Mountpoint: container.MountPoint()},
+			UsedBytes:  &types.UInt64Value{Value: sample.DiskUsedBytes},
+			InodesUsed: &types.UInt64Value{Value: sample.DiskInodesUsed},
+		},
+	}
+}
+
 // buildContainerStats takes stats directly from the container, and attempts to inject the filesystem
 // usage of the container.
 // This is not taken care of by the container because we access information on the server level (storage driver).
 func (s *Server) buildContainerStats(ctx context.Context, stats *oci.ContainerStats, container *oci.Container) *types.ContainerStats {
-	// TODO: Fix this for other storage drivers. This will only work with overlay.
-	var writableLayer *types.FilesystemUsage
-	if s.ContainerServer.Config().RootConfig.Storage == "overlay" {
-		diffDir := filepath.Join(filepath.Dir(container.MountPoint()), "diff")
-		bytesUsed, inodeUsed, err := statsmgr.GetDiskUsageStats(diffDir)
-		if err != nil {
-			log.Warnf(ctx, "unable to get disk usage for container %s， %s", container.ID(), err)
-		}
-		writableLayer = &types.FilesystemUsage{
-			Timestamp:  stats.SystemNano,
-			FsID:       &types.FilesystemIdentifier{Mountpoint: container.MountPoint()},
-			UsedBytes:  &types.UInt64Value{Value: bytesUsed},
-			InodesUsed: &types.UInt64Value{Value: inodeUsed},
-		}
+	bytesUsed, inodeUsed, err := s.DiskUsageBackend().DiskUsage(container.MountPoint())
+	if err != nil {
+		log.Warnf(ctx, "unable to get disk usage for container %s， %s", container.ID(), err)
+	}
+	writableLayer := &types.FilesystemUsage{
+		Timestamp:  stats.SystemNano,
+		FsID:       &types.FilesystemIdentifier{Mountpoint: container.MountPoint()},
+		UsedBytes:  &types.UInt64Value{Value: bytesUsed},
+		InodesUsed: &types.UInt64Value{Value: inodeUsed},
 	}
 	return &types.ContainerStats{
 		Attributes: &types.ContainerAttributes{
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"context"
+
+	oci "github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/server/cri/types"
+	"github.com/pkg/errors"
+)
+
+// ContainerStatus returns status of the container. If the container does not
+// exist, the call returns an error.
+func (s *Server) ContainerStatus(ctx context.Context, req *types.ContainerStatusRequest) (*types.ContainerStatusResponse, error) {
+	c, err := s.GetContainerFromShortID(req.ContainerID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not find container %q", req.ContainerID)
+	}
+
+	status := s.buildContainerStatus(c)
+
+	return &types.ContainerStatusResponse{Status: status}, nil
+}
+
+// buildContainerStatus assembles the CRI ContainerStatus for c, including the
+// Linux resources currently applied to it so that callers doing in-place
+// vertical scaling can read back what actually took effect after an
+// UpdateContainerResources call.
+func (s *Server) buildContainerStatus(c *oci.Container) *types.ContainerStatus {
+	return &types.ContainerStatus{
+		ID: c.ID(),
+		Metadata: &types.ContainerMetadata{
+			Name:    c.Metadata().Name,
+			Attempt: c.Metadata().Attempt,
+		},
+		Labels:      c.Labels(),
+		Annotations: c.Annotations(),
+		LogPath:     c.LogPath(),
+		Resources:   containerResourcesStatus(c),
+	}
+}
+
+// containerResourcesStatus builds the Resources field of ContainerStatus
+// from the Linux resources currently applied to c, i.e. the result of the
+// most recent successful UpdateContainerResources call. It returns nil if
+// the container has never been live-updated, so Resources is left unset
+// exactly as it was before resource updates existed.
+func containerResourcesStatus(c *oci.Container) *types.ContainerResources {
+	r := c.LinuxResources()
+	if r == nil {
+		return nil
+	}
+	return &types.ContainerResources{Linux: r}
+}
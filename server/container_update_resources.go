@@ -21,22 +21,39 @@ func (s *Server) UpdateContainerResources(ctx context.Context, req *types.Update
 	}
 
 	if req.Linux != nil {
-		resources := toOCIResources(req.Linux)
+		resources, err := toOCIResources(req.Linux)
+		if err != nil {
+			return err
+		}
 		if err := s.Runtime().UpdateContainer(c, resources); err != nil {
 			return err
 		}
 
 		// update memory store with updated resources
 		s.UpdateContainerLinuxResources(c, resources)
+
+		// record what was actually applied so ContainerStatus can report it back
+		c.SetLinuxResources(req.Linux)
 	}
 
 	return nil
 }
 
-// toOCIResources converts CRI resource constraints to OCI.
-func toOCIResources(r *types.LinuxContainerResources) *rspec.LinuxResources {
+// toOCIResources converts CRI resource constraints to OCI, rejecting any
+// constraint that the host's cgroup version cannot apply (e.g. blkio weight
+// and realtime CPU bandwidth are cgroup v1 only, Unified is cgroup v2 only)
+// rather than silently ignoring it.
+func toOCIResources(r *types.LinuxContainerResources) (*rspec.LinuxResources, error) {
+	isV2 := node.CgroupIsV2()
+
+	// OomScoreAdj is a Process-level field at create time, and neither runc
+	// nor crun update support changing it on a running container, so it must
+	// be rejected here rather than silently dropped like before.
+	if r.OomScoreAdj != 0 {
+		return nil, errors.New("oom_score_adj cannot be changed after container creation")
+	}
+
 	update := rspec.LinuxResources{
-		// TODO(runcom): OOMScoreAdj is missing
 		CPU: &rspec.LinuxCPU{
 			Cpus: r.CPUsetCPUs,
 			Mems: r.CPUsetMems,
@@ -52,6 +69,13 @@ func toOCIResources(r *types.LinuxContainerResources) *rspec.LinuxResources {
 	if r.CPUQuota != 0 {
 		update.CPU.Quota = proto.Int64(r.CPUQuota)
 	}
+	if r.CpuRtRuntime != 0 || r.CpuRtPeriod != 0 {
+		if isV2 {
+			return nil, errors.New("cpu realtime runtime/period cannot be updated on a cgroup v2 host")
+		}
+		update.CPU.RealtimeRuntime = proto.Int64(r.CpuRtRuntime)
+		update.CPU.RealtimePeriod = proto.Uint64(uint64(r.CpuRtPeriod))
+	}
 
 	memory := r.MemoryLimitInBytes
 	if memory != 0 {
@@ -61,5 +85,75 @@ func toOCIResources(r *types.LinuxContainerResources) *rspec.LinuxResources {
 			update.Memory.Swap = proto.Int64(memory)
 		}
 	}
-	return &update
+	if r.MemorySwapLimitInBytes != 0 {
+		update.Memory.Swap = proto.Int64(r.MemorySwapLimitInBytes)
+	}
+	if r.MemoryReservation != 0 {
+		update.Memory.Reservation = proto.Int64(r.MemoryReservation)
+	}
+
+	if len(r.HugepageLimits) > 0 {
+		for _, limit := range r.HugepageLimits {
+			update.HugepageLimits = append(update.HugepageLimits, rspec.LinuxHugepageLimit{
+				Pagesize: limit.PageSize,
+				Limit:    limit.Limit,
+			})
+		}
+	}
+
+	if r.PidsLimit != 0 {
+		update.Pids = &rspec.LinuxPids{Limit: r.PidsLimit}
+	}
+
+	if r.BlockioWeight != 0 || len(r.BlockioWeightDevice) > 0 || len(r.BlockioThrottleReadBpsDevice) > 0 || len(r.BlockioThrottleWriteBpsDevice) > 0 || len(r.BlockioThrottleReadIopsDevice) > 0 || len(r.BlockioThrottleWriteIopsDevice) > 0 {
+		if isV2 {
+			return nil, errors.New("blkio weight and device throttles cannot be updated on a cgroup v2 host")
+		}
+		update.BlockIO = toOCIBlockIO(r)
+	}
+
+	if len(r.Unified) > 0 {
+		if !isV2 {
+			return nil, errors.New("unified cgroup values can only be updated on a cgroup v2 host")
+		}
+		update.Unified = r.Unified
+	}
+
+	return &update, nil
+}
+
+// toOCIBlockIO converts the CRI blkio weight and per-device throttle
+// constraints to their OCI (cgroup v1) equivalents.
+func toOCIBlockIO(r *types.LinuxContainerResources) *rspec.LinuxBlockIO {
+	blockIO := &rspec.LinuxBlockIO{}
+	if r.BlockioWeight != 0 {
+		weight := uint16(r.BlockioWeight)
+		blockIO.Weight = &weight
+	}
+	for _, d := range r.BlockioWeightDevice {
+		blockIO.WeightDevice = append(blockIO.WeightDevice, rspec.LinuxWeightDevice{
+			LinuxBlockIODevice: rspec.LinuxBlockIODevice{Major: d.Major, Minor: d.Minor},
+			Weight:             proto.Uint16(uint16(d.Weight)),
+		})
+	}
+	for _, d := range r.BlockioThrottleReadBpsDevice {
+		blockIO.ThrottleReadBpsDevice = append(blockIO.ThrottleReadBpsDevice, toOCIThrottleDevice(d))
+	}
+	for _, d := range r.BlockioThrottleWriteBpsDevice {
+		blockIO.ThrottleWriteBpsDevice = append(blockIO.ThrottleWriteBpsDevice, toOCIThrottleDevice(d))
+	}
+	for _, d := range r.BlockioThrottleReadIopsDevice {
+		blockIO.ThrottleReadIOPSDevice = append(blockIO.ThrottleReadIOPSDevice, toOCIThrottleDevice(d))
+	}
+	for _, d := range r.BlockioThrottleWriteIopsDevice {
+		blockIO.ThrottleWriteIOPSDevice = append(blockIO.ThrottleWriteIOPSDevice, toOCIThrottleDevice(d))
+	}
+	return blockIO
+}
+
+func toOCIThrottleDevice(d *types.ThrottleDevice) rspec.LinuxThrottleDevice {
+	return rspec.LinuxThrottleDevice{
+		LinuxBlockIODevice: rspec.LinuxBlockIODevice{Major: d.Major, Minor: d.Minor},
+		Rate:               d.Rate,
+	}
 }
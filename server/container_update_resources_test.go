@@ -0,0 +1,70 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/cri-o/cri-o/server/cri/types"
+)
+
+func TestToOCIResourcesCPUAndMemory(t *testing.T) {
+	resources, err := toOCIResources(&types.LinuxContainerResources{
+		CPUShares:          512,
+		CPUPeriod:          100000,
+		CPUQuota:           50000,
+		CPUsetCPUs:         "0-1",
+		CPUsetMems:         "0",
+		MemoryLimitInBytes: 1024,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resources.CPU.Cpus != "0-1" || resources.CPU.Mems != "0" {
+		t.Errorf("unexpected cpuset: %+v", resources.CPU)
+	}
+	if *resources.CPU.Shares != 512 {
+		t.Errorf("unexpected shares: %v", *resources.CPU.Shares)
+	}
+	if *resources.Memory.Limit != 1024 {
+		t.Errorf("unexpected memory limit: %v", *resources.Memory.Limit)
+	}
+}
+
+func TestToOCIResourcesRejectsOomScoreAdj(t *testing.T) {
+	_, err := toOCIResources(&types.LinuxContainerResources{OomScoreAdj: 500})
+	if err == nil {
+		t.Fatal("expected an error for oom_score_adj, got nil")
+	}
+}
+
+func TestToOCIResourcesHugepagesAndPids(t *testing.T) {
+	resources, err := toOCIResources(&types.LinuxContainerResources{
+		PidsLimit: 42,
+		HugepageLimits: []*types.HugepageLimit{
+			{PageSize: "2MB", Limit: 1024},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resources.Pids == nil || resources.Pids.Limit != 42 {
+		t.Errorf("unexpected pids limit: %+v", resources.Pids)
+	}
+	if len(resources.HugepageLimits) != 1 || resources.HugepageLimits[0].Pagesize != "2MB" {
+		t.Errorf("unexpected hugepage limits: %+v", resources.HugepageLimits)
+	}
+}
+
+func TestToOCIBlockIO(t *testing.T) {
+	blockIO := toOCIBlockIO(&types.LinuxContainerResources{
+		BlockioWeight: 500,
+		BlockioThrottleReadBpsDevice: []*types.ThrottleDevice{
+			{Major: 8, Minor: 0, Rate: 1024},
+		},
+	})
+	if blockIO.Weight == nil || *blockIO.Weight != 500 {
+		t.Errorf("unexpected blkio weight: %+v", blockIO.Weight)
+	}
+	if len(blockIO.ThrottleReadBpsDevice) != 1 || blockIO.ThrottleReadBpsDevice[0].Rate != 1024 {
+		t.Errorf("unexpected read bps throttle: %+v", blockIO.ThrottleReadBpsDevice)
+	}
+}
@@ -0,0 +1,9 @@
+package types
+
+// ContainerResources holds the resources currently applied to a container,
+// as reported by ContainerStatus's Resources field so that kubelet's
+// in-place pod vertical scaling can read back what actually took effect
+// after an UpdateContainerResources call.
+type ContainerResources struct {
+	Linux *LinuxContainerResources
+}
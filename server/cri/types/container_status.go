@@ -0,0 +1,25 @@
+package types
+
+// ContainerStatusRequest is the request for ContainerStatus.
+type ContainerStatusRequest struct {
+	ContainerID string
+}
+
+// ContainerStatusResponse is the response for ContainerStatus.
+type ContainerStatusResponse struct {
+	Status *ContainerStatus
+}
+
+// ContainerStatus represents the status of a container.
+type ContainerStatus struct {
+	ID          string
+	Metadata    *ContainerMetadata
+	Labels      map[string]string
+	Annotations map[string]string
+	LogPath     string
+	// Resources is the set of resources currently applied to the container.
+	// It reflects the result of the most recent successful
+	// UpdateContainerResources call, so that kubelet in-place pod vertical
+	// scaling can read back what actually took effect.
+	Resources *ContainerResources
+}
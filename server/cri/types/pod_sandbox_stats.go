@@ -0,0 +1,81 @@
+package types
+
+// PodSandboxStatsRequest is the request for PodSandboxStats.
+type PodSandboxStatsRequest struct {
+	PodSandboxID string
+}
+
+// PodSandboxStatsResponse is the response for PodSandboxStats.
+type PodSandboxStatsResponse struct {
+	Stats *PodSandboxStats
+}
+
+// PodSandboxStatsFilter narrows the set of sandboxes ListPodSandboxStats
+// reports on. An empty filter matches every sandbox.
+type PodSandboxStatsFilter struct {
+	ID            string
+	LabelSelector map[string]string
+}
+
+// ListPodSandboxStatsRequest is the request for ListPodSandboxStats.
+type ListPodSandboxStatsRequest struct {
+	Filter *PodSandboxStatsFilter
+}
+
+// ListPodSandboxStatsResponse is the response for ListPodSandboxStats.
+type ListPodSandboxStatsResponse struct {
+	Stats []*PodSandboxStats
+}
+
+// PodSandboxStats provides the resource usage statistics for a pod sandbox,
+// along with the stats of every container running inside it.
+type PodSandboxStats struct {
+	Attributes *PodSandboxAttributes
+	Linux      *LinuxPodSandboxStats
+}
+
+// PodSandboxAttributes provides the identifying information for a pod
+// sandbox in PodSandboxStats.
+type PodSandboxAttributes struct {
+	ID          string
+	Metadata    *PodSandboxMetadata
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// LinuxPodSandboxStats provides the resource usage statistics for a Linux
+// pod sandbox. CPU and Memory are aggregated from the sandbox's own cgroup
+// (which is the cgroup parent of every container in the pod), Network is
+// resolved from the sandbox's network namespace, Process is the number of
+// tasks running inside the sandbox, and Containers mirrors the per-container
+// stats already returned by ContainerStats.
+type LinuxPodSandboxStats struct {
+	CPU        *CPUUsage
+	Memory     *MemoryUsage
+	Network    *NetworkUsage
+	Process    *ProcessUsage
+	Containers []*ContainerStats
+}
+
+// NetworkUsage provides the network usage statistics for a pod sandbox.
+type NetworkUsage struct {
+	Timestamp        int64
+	DefaultInterface *NetworkInterfaceUsage
+	Interfaces       []*NetworkInterfaceUsage
+}
+
+// NetworkInterfaceUsage provides the network usage statistics for a single
+// network interface.
+type NetworkInterfaceUsage struct {
+	Name     string
+	RxBytes  *UInt64Value
+	RxErrors *UInt64Value
+	TxBytes  *UInt64Value
+	TxErrors *UInt64Value
+}
+
+// ProcessUsage provides the process count for a pod sandbox.
+type ProcessUsage struct {
+	Timestamp    int64
+	ProcessCount *UInt64Value
+}
@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/config/statsmgr"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/server/cri/types"
+	"github.com/pkg/errors"
+)
+
+// PodSandboxStats returns stats for the sandbox. If the sandbox does not
+// exist, the call returns an error.
+func (s *Server) PodSandboxStats(ctx context.Context, req *types.PodSandboxStatsRequest) (*types.PodSandboxStatsResponse, error) {
+	sb := s.GetSandbox(req.PodSandboxID)
+	if sb == nil {
+		return nil, errors.Errorf("unable to find sandbox %s", req.PodSandboxID)
+	}
+
+	stats, err := s.sandboxStats(ctx, sb)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.PodSandboxStatsResponse{Stats: stats}, nil
+}
+
+// ListPodSandboxStats returns stats for every sandbox matching the filter in
+// the request, or every sandbox known to the server if no filter is given.
+func (s *Server) ListPodSandboxStats(ctx context.Context, req *types.ListPodSandboxStatsRequest) (*types.ListPodSandboxStatsResponse, error) {
+	sboxes := s.ContainerServer.ListSandboxes()
+
+	allStats := make([]*types.PodSandboxStats, 0, len(sboxes))
+	for _, sb := range sboxes {
+		if !matchesPodSandboxStatsFilter(req.Filter, sb) {
+			continue
+		}
+		stats, err := s.sandboxStats(ctx, sb)
+		if err != nil {
+			log.Warnf(ctx, "unable to get stats for sandbox %s: %v", sb.ID(), err)
+			continue
+		}
+		allStats = append(allStats, stats)
+	}
+
+	return &types.ListPodSandboxStatsResponse{Stats: allStats}, nil
+}
+
+// matchesPodSandboxStatsFilter reports whether sb matches every constraint
+// in filter. A nil filter, or one with every field left empty, matches all
+// sandboxes.
+func matchesPodSandboxStatsFilter(filter *types.PodSandboxStatsFilter, sb *sandbox.Sandbox) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.ID != "" && filter.ID != sb.ID() {
+		return false
+	}
+	labels := sb.Labels()
+	for k, v := range filter.LabelSelector {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// sandboxStats builds a PodSandboxStats for sb by combining its own cgroup
+// usage and network namespace counters with the stats of the containers
+// running inside it. CPU/memory are read through the shared StatsManager
+// cache, which tracks sandboxes as well as containers, so that once
+// background collection is enabled, sandboxes benefit from it the same way
+// containers do instead of always hitting the cgroup filesystem
+// synchronously.
+func (s *Server) sandboxStats(ctx context.Context, sb *sandbox.Sandbox) (*types.PodSandboxStats, error) {
+	containers := s.ContainerServer.ListContainersBySandbox(sb.ID())
+
+	containerStats, errs := s.CRIStatsForContainers(ctx, containers...)
+	if len(errs) > 0 {
+		log.Warnf(ctx, "errors collecting container stats for sandbox %s: %v", sb.ID(), errs)
+	}
+
+	mgr := s.StatsManager()
+	mgr.Track(sb.ID())
+
+	var cgroupStats *statsmgr.SandboxCgroupStats
+	if rate := mgr.Get(sb.ID()); rate != nil {
+		cgroupStats = &statsmgr.SandboxCgroupStats{
+			CPUNano:         rate.Current.CPUNano,
+			WorkingSetBytes: rate.Current.WorkingSetBytes,
+			ProcessCount:    rate.Current.ProcessCount,
+		}
+	} else {
+		var err error
+		cgroupStats, err = statsmgr.GetSandboxCgroupStats(sb.CgroupParent())
+		if err != nil {
+			return nil, errors.Wrapf(err, "get cgroup stats for sandbox %s", sb.ID())
+		}
+	}
+
+	defaultIface, allIfaces, err := statsmgr.GetSandboxNetworkStats(sb.NetNsPath())
+	if err != nil {
+		log.Warnf(ctx, "unable to get network stats for sandbox %s: %v", sb.ID(), err)
+	}
+
+	now := time.Now().UnixNano()
+
+	linux := &types.LinuxPodSandboxStats{
+		CPU: &types.CPUUsage{
+			Timestamp:            now,
+			UsageCoreNanoSeconds: &types.UInt64Value{Value: cgroupStats.CPUNano},
+		},
+		Memory: &types.MemoryUsage{
+			Timestamp:       now,
+			WorkingSetBytes: &types.UInt64Value{Value: cgroupStats.WorkingSetBytes},
+		},
+		Process: &types.ProcessUsage{
+			Timestamp:    now,
+			ProcessCount: &types.UInt64Value{Value: cgroupStats.ProcessCount},
+		},
+		Network:    buildNetworkUsage(now, defaultIface, allIfaces),
+		Containers: containerStats,
+	}
+
+	return &types.PodSandboxStats{
+		Attributes: &types.PodSandboxAttributes{
+			ID:          sb.ID(),
+			Metadata:    sb.Metadata(),
+			Labels:      sb.Labels(),
+			Annotations: sb.Annotations(),
+		},
+		Linux: linux,
+	}, nil
+}
+
+func buildNetworkUsage(now int64, defaultIface *statsmgr.InterfaceStats, all []*statsmgr.InterfaceStats) *types.NetworkUsage {
+	usage := &types.NetworkUsage{Timestamp: now}
+	if defaultIface != nil {
+		usage.DefaultInterface = toNetworkInterfaceUsage(defaultIface)
+	}
+	for _, iface := range all {
+		usage.Interfaces = append(usage.Interfaces, toNetworkInterfaceUsage(iface))
+	}
+	return usage
+}
+
+func toNetworkInterfaceUsage(iface *statsmgr.InterfaceStats) *types.NetworkInterfaceUsage {
+	return &types.NetworkInterfaceUsage{
+		Name:     iface.Name,
+		RxBytes:  &types.UInt64Value{Value: iface.RxBytes},
+		RxErrors: &types.UInt64Value{Value: iface.RxErrors},
+		TxBytes:  &types.UInt64Value{Value: iface.TxBytes},
+		TxErrors: &types.UInt64Value{Value: iface.TxErrors},
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/config/statsmgr"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	oci "github.com/cri-o/cri-o/internal/oci"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+var (
+	statsManagerOnce sync.Once
+	statsManagerInst *statsmgr.Manager
+
+	diskUsageBackendOnce sync.Once
+	diskUsageBackendInst statsmgr.DiskUsageBackend
+)
+
+// StatsManager returns the server-wide background metrics collector. It is
+// lazily constructed and started on first use, sampling every known
+// container and sandbox at the configured collection period. Collection is
+// disabled when stats_collection_period is unset or non-positive.
+func (s *Server) StatsManager() *statsmgr.Manager {
+	statsManagerOnce.Do(func() {
+		period := s.ContainerServer.Config().RuntimeConfig.StatsCollectionPeriod
+		statsManagerInst = statsmgr.NewManager(period, s.collectStatsSample)
+		statsManagerInst.Start()
+	})
+	return statsManagerInst
+}
+
+// collectStatsSample is the statsmgr.CollectFunc backing StatsManager: id is
+// either a container ID or a sandbox ID, and this dispatches to whichever one
+// it resolves to so a single Manager can track both.
+func (s *Server) collectStatsSample(id string) (*statsmgr.Sample, error) {
+	if c, err := s.GetContainerFromShortID(id); err == nil {
+		return s.collectContainerSample(c)
+	}
+	if sb := s.GetSandbox(id); sb != nil {
+		return s.collectSandboxSample(sb)
+	}
+	return nil, errors.Errorf("id %s is neither a known container nor a known sandbox", id)
+}
+
+func (s *Server) collectContainerSample(c *oci.Container) (*statsmgr.Sample, error) {
+	sb := s.GetSandbox(c.Sandbox())
+	if sb == nil {
+		return nil, errors.Errorf("sandbox %s not found for container %s", c.Sandbox(), c.ID())
+	}
+
+	ociStat, err := s.Runtime().ContainerStats(c, sb.CgroupParent())
+	if err != nil {
+		return nil, err
+	}
+
+	bytesUsed, inodesUsed, err := s.DiskUsageBackend().DiskUsage(c.MountPoint())
+	if err != nil {
+		log.Warnf(context.Background(), "unable to get disk usage for container %s: %v", c.ID(), err)
+	}
+
+	return &statsmgr.Sample{
+		Timestamp:       ociStat.SystemNano,
+		CPUNano:         ociStat.CPUNano,
+		WorkingSetBytes: ociStat.WorkingSetBytes,
+		DiskUsedBytes:   bytesUsed,
+		DiskInodesUsed:  inodesUsed,
+	}, nil
+}
+
+func (s *Server) collectSandboxSample(sb *sandbox.Sandbox) (*statsmgr.Sample, error) {
+	cgroupStats, err := statsmgr.GetSandboxCgroupStats(sb.CgroupParent())
+	if err != nil {
+		return nil, err
+	}
+	return &statsmgr.Sample{
+		Timestamp:       time.Now().UnixNano(),
+		CPUNano:         cgroupStats.CPUNano,
+		WorkingSetBytes: cgroupStats.WorkingSetBytes,
+		ProcessCount:    cgroupStats.ProcessCount,
+	}, nil
+}
+
+// DiskUsageBackend returns the storage-driver-specific disk usage backend,
+// selected once at first use from the server's configured storage driver and
+// options.
+func (s *Server) DiskUsageBackend() statsmgr.DiskUsageBackend {
+	diskUsageBackendOnce.Do(func() {
+		root := s.ContainerServer.Config().RootConfig
+		backend, err := statsmgr.NewDiskUsageBackend(root.Storage, root.StorageOptions)
+		if err != nil {
+			log.Errorf(context.Background(), "unable to select disk usage backend, disk usage will be unavailable: %v", err)
+			backend = statsmgr.NewNoopDiskUsageBackend()
+		}
+		diskUsageBackendInst = backend
+	})
+	return diskUsageBackendInst
+}